@@ -0,0 +1,46 @@
+package incache
+
+// EvictionReason describes why an entry left a cache so that OnEvict callbacks can
+// react differently to, say, an explicit Delete versus a TTL expiring.
+type EvictionReason int
+
+const (
+	// EvictionDeleted indicates the entry was removed by an explicit Delete call.
+	EvictionDeleted EvictionReason = iota
+	// EvictionCapacity indicates the entry was evicted to make room for a new one
+	// once the cache reached its configured size.
+	EvictionCapacity
+	// EvictionExpired indicates the entry was removed because its TTL elapsed.
+	EvictionExpired
+)
+
+// String returns a human-readable name for the eviction reason.
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictionDeleted:
+		return "deleted"
+	case EvictionCapacity:
+		return "capacity"
+	case EvictionExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
+
+// evictionEvent[K, V] carries the key, value and reason for a single entry that left
+// a cache. Events are collected while a cache's lock is held and dispatched to any
+// registered OnEvict callback only after the lock has been released.
+type evictionEvent[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictionReason
+}
+
+// evictCallback and insertionCallback are the named function types behind each cache's
+// onEvict/onInsertion fields. Naming them lets those fields live in an atomic.Pointer,
+// so OnEvict/OnInsertion can be (re)registered concurrently with Set/Get/Delete without
+// racing the dispatch helpers that read them after the cache's lock has been released.
+type evictCallback[K comparable, V any] func(k K, v V, reason EvictionReason)
+
+type insertionCallback[K comparable, V any] func(k K, v V)
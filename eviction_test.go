@@ -0,0 +1,77 @@
+package incache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestLRUCacheOnEvictReasons asserts OnEvict fires with the correct EvictionReason for
+// a capacity eviction, an explicit Delete and a TTL expiry.
+func TestLRUCacheOnEvictReasons(t *testing.T) {
+	c := NewLRU[string, int](1)
+
+	var mu sync.Mutex
+	var reasons []EvictionReason
+	c.OnEvict(func(k string, v int, reason EvictionReason) {
+		mu.Lock()
+		reasons = append(reasons, reason)
+		mu.Unlock()
+	})
+
+	c.Set("a", 1)
+	c.Set("b", 2) // evicts "a" for capacity, size is 1
+
+	c.Delete("b")
+
+	c.SetWithTimeout("c", 3, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	c.Get("c") // triggers the expiry branch in GetWithStale
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reasons) != 3 {
+		t.Fatalf("got %d eviction events, want 3: %v", len(reasons), reasons)
+	}
+	if reasons[0] != EvictionCapacity {
+		t.Errorf("reasons[0] = %v, want EvictionCapacity", reasons[0])
+	}
+	if reasons[1] != EvictionDeleted {
+		t.Errorf("reasons[1] = %v, want EvictionDeleted", reasons[1])
+	}
+	if reasons[2] != EvictionExpired {
+		t.Errorf("reasons[2] = %v, want EvictionExpired", reasons[2])
+	}
+}
+
+// TestLRUCacheOnInsertion asserts OnInsertion fires on every Set (including updates of
+// an existing key, per Set's own contract), and on NotFoundSet only when the key was
+// actually absent.
+func TestLRUCacheOnInsertion(t *testing.T) {
+	c := NewLRU[string, int](10)
+
+	var mu sync.Mutex
+	var inserted []string
+	c.OnInsertion(func(k string, v int) {
+		mu.Lock()
+		inserted = append(inserted, k)
+		mu.Unlock()
+	})
+
+	c.Set("a", 1)
+	c.Set("a", 2) // Set always dispatches insertion, even on update
+	c.NotFoundSet("b", 3)
+	c.NotFoundSet("b", 4) // already exists, no insertion
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"a", "a", "b"}
+	if len(inserted) != len(want) {
+		t.Fatalf("got %d insertion events, want %d: %v", len(inserted), len(want), inserted)
+	}
+	for i, k := range want {
+		if inserted[i] != k {
+			t.Errorf("inserted[%d] = %q, want %q", i, inserted[i], k)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package incache
+
+import "time"
+
+// expEntry is a single scheduled expiration. generation ties the entry back to the
+// valueWithTimeout it was created for, so that overwriting or deleting a key doesn't
+// require touching the heap: a stale entry is simply discarded when it is popped.
+type expEntry[K comparable] struct {
+	key        K
+	expireAt   time.Time
+	generation uint64
+}
+
+// expHeap is a min-heap of expEntry ordered by expireAt, giving the scheduler
+// goroutine O(log N) inserts and O(1) access to the next deadline instead of an O(N)
+// scan of the whole map.
+type expHeap[K comparable] []expEntry[K]
+
+func (h expHeap[K]) Len() int { return len(h) }
+
+func (h expHeap[K]) Less(i, j int) bool { return h[i].expireAt.Before(h[j].expireAt) }
+
+func (h expHeap[K]) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *expHeap[K]) Push(x any) {
+	*h = append(*h, x.(expEntry[K]))
+}
+
+func (h *expHeap[K]) Pop() any {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	*h = old[:n-1]
+	return entry
+}
@@ -0,0 +1,72 @@
+package incache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestMCacheSchedulerExpiresEntries exercises the heap-based scheduler end to end:
+// entries set with a short timeout should be evicted (and reported via OnEvict) on
+// their own, without any caller ever calling Get on them.
+func TestMCacheSchedulerExpiresEntries(t *testing.T) {
+	c := newManual[string, int](&CacheBuilder[string, int]{size: 10})
+	defer c.stop()
+
+	var mu sync.Mutex
+	expired := make(map[string]bool)
+	c.OnEvict(func(k string, v int, reason EvictionReason) {
+		if reason != EvictionExpired {
+			return
+		}
+		mu.Lock()
+		expired[k] = true
+		mu.Unlock()
+	})
+
+	c.SetWithTimeout("a", 1, 10*time.Millisecond)
+	c.SetWithTimeout("b", 2, 200*time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		done := expired["a"]
+		mu.Unlock()
+		if done {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !expired["a"] {
+		t.Fatal("entry a was not expired by the scheduler")
+	}
+	if expired["b"] {
+		t.Fatal("entry b expired too early")
+	}
+}
+
+// TestMCacheSchedulerConcurrentAccess drives Set/Get/Delete from multiple goroutines
+// concurrently with the background scheduler, so -race can catch any unsynchronized
+// access to the heap or the map.
+func TestMCacheSchedulerConcurrentAccess(t *testing.T) {
+	c := newManual[string, int](&CacheBuilder[string, int]{size: 100})
+	defer c.stop()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				k := string(rune('a' + (j+i)%8))
+				c.SetWithTimeout(k, j, time.Millisecond)
+				c.Get(k)
+				c.Delete(k)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,62 @@
+package incache
+
+import (
+	"fmt"
+	"time"
+)
+
+// GetOrLoad returns the value for k, loading it via loader on a miss. Concurrent
+// misses for the same key are deduplicated so that loader runs at most once per key
+// at a time, even under heavy contention. On success, the loaded value is stored with
+// the returned TTL (a TTL of 0 means no expiry), matching SetWithTimeout semantics.
+func (c *MCache[K, V]) GetOrLoad(k K, loader func(K) (V, time.Duration, error)) (V, error) {
+	if v, ok := c.Get(k); ok {
+		return v, nil
+	}
+
+	v, err, _ := c.sfGroup.Do(fmt.Sprint(k), func() (any, error) {
+		if v, ok := c.Get(k); ok {
+			return v, nil
+		}
+
+		v, ttl, err := loader(k)
+		if err != nil {
+			return v, err
+		}
+		c.SetWithTimeout(k, v, ttl)
+		return v, nil
+	})
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return v.(V), nil
+}
+
+// GetOrLoad returns the value for k, loading it via loader on a miss. Concurrent
+// misses for the same key are deduplicated so that loader runs at most once per key
+// at a time, even under heavy contention. On success, the loaded value is stored with
+// the returned TTL (a TTL of 0 means no expiry), matching SetWithTimeout semantics.
+func (c *LRUCache[K, V]) GetOrLoad(k K, loader func(K) (V, time.Duration, error)) (V, error) {
+	if v, ok := c.Get(k); ok {
+		return v, nil
+	}
+
+	v, err, _ := c.sfGroup.Do(fmt.Sprint(k), func() (any, error) {
+		if v, ok := c.Get(k); ok {
+			return v, nil
+		}
+
+		v, ttl, err := loader(k)
+		if err != nil {
+			return v, err
+		}
+		c.SetWithTimeout(k, v, ttl)
+		return v, nil
+	})
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return v.(V), nil
+}
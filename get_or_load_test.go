@@ -0,0 +1,103 @@
+package incache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLRUCacheGetOrLoadDeduplicatesConcurrentMisses(t *testing.T) {
+	c := NewLRU[string, int](10)
+
+	var calls int32
+	loader := func(k string) (int, time.Duration, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond) // widen the window for concurrent misses to overlap
+		return 42, 0, nil
+	}
+
+	const n = 20
+	var wg sync.WaitGroup
+	results := make([]int, n)
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			v, err := c.GetOrLoad("k", loader)
+			if err != nil {
+				t.Errorf("GetOrLoad: %v", err)
+			}
+			results[i] = v
+		}(i)
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("loader called %d times, want 1", got)
+	}
+	for i, v := range results {
+		if v != 42 {
+			t.Errorf("results[%d] = %d, want 42", i, v)
+		}
+	}
+}
+
+func TestLRUCacheGetOrLoadPropagatesError(t *testing.T) {
+	c := NewLRU[string, int](10)
+	wantErr := errors.New("load failed")
+
+	_, err := c.GetOrLoad("k", func(k string) (int, time.Duration, error) {
+		return 0, 0, wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+	if _, ok := c.Get("k"); ok {
+		t.Fatal("a failed load should not populate the cache")
+	}
+}
+
+func TestLRUCacheGetOrLoadForwardsTTL(t *testing.T) {
+	c := NewLRU[string, int](10)
+
+	v, err := c.GetOrLoad("k", func(k string) (int, time.Duration, error) {
+		return 1, 10 * time.Millisecond, nil
+	})
+	if err != nil {
+		t.Fatalf("GetOrLoad: %v", err)
+	}
+	if v != 1 {
+		t.Fatalf("v = %d, want 1", v)
+	}
+
+	if _, ok := c.Get("k"); !ok {
+		t.Fatal("value should still be cached immediately after load")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := c.Get("k"); ok {
+		t.Fatal("value should have expired per the loader-returned TTL")
+	}
+}
+
+func TestLRUCacheGetOrLoadHit(t *testing.T) {
+	c := NewLRU[string, int](10)
+	c.Set("k", 7)
+
+	called := false
+	v, err := c.GetOrLoad("k", func(k string) (int, time.Duration, error) {
+		called = true
+		return 0, 0, nil
+	})
+	if err != nil {
+		t.Fatalf("GetOrLoad: %v", err)
+	}
+	if called {
+		t.Fatal("loader should not be called on a cache hit")
+	}
+	if v != 7 {
+		t.Fatalf("v = %d, want 7", v)
+	}
+}
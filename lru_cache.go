@@ -2,14 +2,19 @@ package incache
 
 import (
 	"container/list"
+	"math/rand"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"golang.org/x/sync/singleflight"
 )
 
 type lruItem[K comparable, V any] struct {
 	key      K
 	value    V
 	expireAt *time.Time
+	ttl      time.Duration // original duration used for expireAt, for sliding expiration
 }
 
 type LRUCache[K comparable, V any] struct {
@@ -17,6 +22,21 @@ type LRUCache[K comparable, V any] struct {
 	size         uint
 	m            map[K]*list.Element // where the key-value pairs are stored
 	evictionList *list.List
+
+	// onEvict/onInsertion are stored behind an atomic.Pointer rather than guarded by
+	// c.mu, since the dispatch helpers read them after c.mu has already been released
+	// (so the callbacks can safely call back into the cache without deadlocking).
+	onEvict     atomic.Pointer[evictCallback[K, V]]
+	onInsertion atomic.Pointer[insertionCallback[K, V]]
+
+	jitterDeviation float64
+	rng             *rand.Rand
+
+	sfGroup singleflight.Group
+
+	defaultMaxAge  time.Duration // applied to entries set without an explicit timeout
+	updateAgeOnGet bool          // sliding expiration: Get refreshes expireAt = now + ttl
+	staleMode      bool          // expired entries are returned (via GetWithStale) instead of deleted
 }
 
 func NewLRU[K comparable, V any](size uint) *LRUCache[K, V] {
@@ -27,88 +47,213 @@ func NewLRU[K comparable, V any](size uint) *LRUCache[K, V] {
 	}
 }
 
+// newLRU creates a new LRU cache instance configured via the provided builder options.
+func newLRU[K comparable, V any](cacheBuilder *CacheBuilder[K, V]) *LRUCache[K, V] {
+	c := &LRUCache[K, V]{
+		size:            cacheBuilder.size,
+		m:               make(map[K]*list.Element),
+		evictionList:    list.New(),
+		jitterDeviation: cacheBuilder.jitterDeviation,
+		defaultMaxAge:   cacheBuilder.defaultMaxAge,
+		updateAgeOnGet:  cacheBuilder.updateAgeOnGet,
+		staleMode:       cacheBuilder.staleOnExpiry,
+	}
+
+	if c.jitterDeviation > 0 {
+		src := cacheBuilder.jitterSource
+		if src == nil {
+			src = rand.NewSource(time.Now().UnixNano())
+		}
+		c.rng = rand.New(src)
+	}
+
+	return c
+}
+
+// jitteredTimeout smears timeout by the configured jitter deviation, so a burst of
+// entries inserted with the same TTL don't all expire at the same instant. It must be
+// called with c.mu held, since the underlying rand.Rand is not safe for concurrent use.
+func (c *LRUCache[K, V]) jitteredTimeout(timeout time.Duration) time.Duration {
+	if c.jitterDeviation <= 0 {
+		return timeout
+	}
+	factor := 1 + c.jitterDeviation*(2*c.rng.Float64()-1)
+	jittered := time.Duration(float64(timeout) * factor)
+	if jittered < time.Millisecond {
+		return time.Millisecond
+	}
+	return jittered
+}
+
+// OnEvict registers a callback invoked whenever an entry leaves the cache, whether by
+// explicit deletion, capacity eviction or TTL expiration. The callback is dispatched
+// after the internal lock has been released, so it is safe to call back into the
+// cache from within it.
+func (c *LRUCache[K, V]) OnEvict(fn func(k K, v V, reason EvictionReason)) {
+	cb := evictCallback[K, V](fn)
+	c.onEvict.Store(&cb)
+}
+
+// OnInsertion registers a callback invoked whenever a new key-value pair is inserted
+// into the cache. The callback is dispatched after the internal lock has been
+// released, so it is safe to call back into the cache from within it.
+func (c *LRUCache[K, V]) OnInsertion(fn func(k K, v V)) {
+	cb := insertionCallback[K, V](fn)
+	c.onInsertion.Store(&cb)
+}
+
+func (c *LRUCache[K, V]) dispatchEvictions(events []evictionEvent[K, V]) {
+	cb := c.onEvict.Load()
+	if cb == nil {
+		return
+	}
+	for _, e := range events {
+		(*cb)(e.key, e.value, e.reason)
+	}
+}
+
+func (c *LRUCache[K, V]) dispatchInsertion(k K, v V) {
+	if cb := c.onInsertion.Load(); cb != nil {
+		(*cb)(k, v)
+	}
+}
+
 func (c *LRUCache[K, V]) Get(k K) (v V, b bool) {
+	v, b, _ = c.GetWithStale(k)
+	return
+}
+
+// GetWithStale behaves like Get, but in addition reports whether the returned value
+// was stale (past its expiration). If the cache was not built with WithStale, expired
+// entries are never returned and stale is always false, matching Get. Otherwise, an
+// expired entry is returned with ok and stale both true instead of being deleted,
+// letting callers serve stale-while-revalidate rather than pay for a synchronous
+// reload on every expiry. Get itself also returns these stale values (with ok true)
+// since it is implemented in terms of GetWithStale; callers that care whether a value
+// was stale must call GetWithStale directly.
+func (c *LRUCache[K, V]) GetWithStale(k K) (v V, ok bool, stale bool) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 
-	item, ok := c.m[k]
-	if !ok {
+	item, exists := c.m[k]
+	if !exists {
+		c.mu.Unlock()
 		return
 	}
+	li := item.Value.(*lruItem[K, V])
+
+	if li.expireAt != nil && li.expireAt.Before(time.Now()) {
+		if !c.staleMode {
+			val := li.value
+			delete(c.m, k)
+			c.evictionList.Remove(item)
+			c.mu.Unlock()
+
+			c.dispatchEvictions([]evictionEvent[K, V]{{key: k, value: val, reason: EvictionExpired}})
+			return
+		}
 
-	if item.Value.(*lruItem[K, V]).expireAt != nil && item.Value.(*lruItem[K, V]).expireAt.Before(time.Now()) {
-		delete(c.m, k)
-		c.evictionList.Remove(item)
+		c.evictionList.MoveToFront(item)
+		v, ok, stale = li.value, true, true
+		c.mu.Unlock()
 		return
 	}
 
+	if c.updateAgeOnGet && li.ttl > 0 {
+		t := time.Now().Add(li.ttl)
+		li.expireAt = &t
+	}
 	c.evictionList.MoveToFront(item)
-
-	return item.Value.(*lruItem[K, V]).value, true
+	v, ok = li.value, true
+	c.mu.Unlock()
+	return
 }
 
 func (c *LRUCache[K, V]) GetAll() map[K]V {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
+	c.mu.Lock()
+	now := time.Now()
 	m := make(map[K]V)
+	var events []evictionEvent[K, V]
 	for k, v := range c.m {
-		if v.Value.(*lruItem[K, V]).expireAt == nil || !v.Value.(*lruItem[K, V]).expireAt.Before(time.Now()) {
-			m[k] = v.Value.(*lruItem[K, V]).value
+		item := v.Value.(*lruItem[K, V])
+		if item.expireAt == nil || !item.expireAt.Before(now) {
+			m[k] = item.value
+		} else if c.staleMode {
+			// Leave the entry in place, same as GetWithStale, so a later
+			// GetWithStale can still serve it; just don't surface it here.
+			continue
+		} else {
+			delete(c.m, k)
+			c.evictionList.Remove(v)
+			events = append(events, evictionEvent[K, V]{key: k, value: item.value, reason: EvictionExpired})
 		}
 	}
+	c.mu.Unlock()
 
+	c.dispatchEvictions(events)
 	return m
 }
 
 func (c *LRUCache[K, V]) Set(k K, v V) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	events := c.set(k, v, c.defaultMaxAge)
+	c.mu.Unlock()
 
-	c.set(k, v, 0)
+	c.dispatchEvictions(events)
+	c.dispatchInsertion(k, v)
 }
 
 func (c *LRUCache[K, V]) SetWithTimeout(k K, v V, t time.Duration) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	events := c.set(k, v, t)
+	c.mu.Unlock()
 
-	c.set(k, v, t)
+	c.dispatchEvictions(events)
+	c.dispatchInsertion(k, v)
 }
 
 func (c *LRUCache[K, V]) NotFoundSet(k K, v V) bool {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	_, ok := c.m[k]
 	if ok {
+		c.mu.Unlock()
 		return false
 	}
 
-	c.set(k, v, 0)
+	events := c.set(k, v, c.defaultMaxAge)
+	c.mu.Unlock()
+
+	c.dispatchEvictions(events)
+	c.dispatchInsertion(k, v)
 	return true
 }
 
 func (c *LRUCache[K, V]) NotFoundSetWithTimeout(k K, v V, t time.Duration) bool {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	_, ok := c.m[k]
 	if ok {
+		c.mu.Unlock()
 		return false
 	}
 
-	c.set(k, v, t)
+	events := c.set(k, v, t)
+	c.mu.Unlock()
+
+	c.dispatchEvictions(events)
+	c.dispatchInsertion(k, v)
 	return true
 }
 
 func (c *LRUCache[K, V]) Delete(k K) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	val, ok := c.delete(k)
+	c.mu.Unlock()
 
-	c.delete(k)
+	if ok {
+		c.dispatchEvictions([]evictionEvent[K, V]{{key: k, value: val, reason: EvictionDeleted}})
+	}
 }
 
-func (c *LRUCache[K, V]) delete(k K) {
+func (c *LRUCache[K, V]) delete(k K) (v V, ok bool) {
 	item, ok := c.m[k]
 	if !ok {
 		return
@@ -116,6 +261,7 @@ func (c *LRUCache[K, V]) delete(k K) {
 
 	delete(c.m, k)
 	c.evictionList.Remove(item)
+	return item.Value.(*lruItem[K, V]).value, true
 }
 
 func (src *LRUCache[K, V]) TransferTo(dst *LRUCache[K, V]) {
@@ -162,16 +308,27 @@ func (c *LRUCache[K, V]) Purge() {
 }
 
 func (c *LRUCache[K, V]) Count() int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
+	c.mu.Lock()
+	now := time.Now()
 	var count int
-	for _, v := range c.m {
-		if v.Value.(*lruItem[K, V]).expireAt == nil || !v.Value.(*lruItem[K, V]).expireAt.Before(time.Now()) {
+	var events []evictionEvent[K, V]
+	for k, v := range c.m {
+		item := v.Value.(*lruItem[K, V])
+		if item.expireAt == nil || !item.expireAt.Before(now) {
 			count++
+		} else if c.staleMode {
+			// Leave the entry in place, same as GetWithStale; just exclude it
+			// from the count.
+			continue
+		} else {
+			delete(c.m, k)
+			c.evictionList.Remove(v)
+			events = append(events, evictionEvent[K, V]{key: k, value: item.value, reason: EvictionExpired})
 		}
 	}
+	c.mu.Unlock()
 
+	c.dispatchEvictions(events)
 	return count
 }
 
@@ -182,40 +339,57 @@ func (c *LRUCache[K, V]) Len() int {
 	return len(c.m)
 }
 
-func (c *LRUCache[K, V]) set(k K, v V, exp time.Duration) {
+// set must be called with c.mu held. exp is the timeout to apply, with zero/negative
+// meaning no expiration; callers that want defaultMaxAge to apply (Set, NotFoundSet)
+// pass c.defaultMaxAge explicitly rather than relying on this function to fall back to
+// it, so that SetWithTimeout(k, v, 0) reliably means "no expiration". It returns the
+// eviction events to dispatch once the caller has released the lock.
+func (c *LRUCache[K, V]) set(k K, v V, exp time.Duration) []evictionEvent[K, V] {
 	item, ok := c.m[k]
 	var tm *time.Time
 	if exp > 0 {
-		t := time.Now().Add(exp)
+		t := time.Now().Add(c.jitteredTimeout(exp))
 		tm = &t
 	}
 	if ok {
 		item.Value.(*lruItem[K, V]).value = v
 		item.Value.(*lruItem[K, V]).expireAt = tm
+		item.Value.(*lruItem[K, V]).ttl = exp
 		c.evictionList.MoveToFront(item)
-	} else {
-		if len(c.m) == int(c.size) {
-			c.evict(1)
-		}
+		return nil
+	}
 
-		lruItem := &lruItem[K, V]{
-			key:      k,
-			value:    v,
-			expireAt: tm,
-		}
+	var events []evictionEvent[K, V]
+	if len(c.m) == int(c.size) {
+		events = c.evict(1)
+	}
 
-		insertedItem := c.evictionList.PushFront(lruItem)
-		c.m[k] = insertedItem
+	lruItem := &lruItem[K, V]{
+		key:      k,
+		value:    v,
+		expireAt: tm,
+		ttl:      exp,
 	}
+
+	insertedItem := c.evictionList.PushFront(lruItem)
+	c.m[k] = insertedItem
+	return events
 }
 
-func (c *LRUCache[K, V]) evict(i int) {
+// evict must be called with c.mu held. It removes up to i entries from the back of
+// the eviction list and returns the eviction events to dispatch once the caller has
+// released the lock.
+func (c *LRUCache[K, V]) evict(i int) []evictionEvent[K, V] {
+	events := make([]evictionEvent[K, V], 0, i)
 	for j := 0; j < i; j++ {
-		if b := c.evictionList.Back(); b != nil {
-			delete(c.m, b.Value.(*lruItem[K, V]).key)
-			c.evictionList.Remove(b)
-		} else {
-			return
+		b := c.evictionList.Back()
+		if b == nil {
+			return events
 		}
+		item := b.Value.(*lruItem[K, V])
+		delete(c.m, item.key)
+		c.evictionList.Remove(b)
+		events = append(events, evictionEvent[K, V]{key: item.key, value: item.value, reason: EvictionCapacity})
 	}
+	return events
 }
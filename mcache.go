@@ -1,72 +1,184 @@
 package incache
 
 import (
+	"container/heap"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"golang.org/x/sync/singleflight"
 )
 
+// MCache is the user-visible handle returned by New for manual (map-based) caches.
+// It wraps an inner *mCache so that, if the cache runs a background expiration
+// goroutine, the goroutine's own reference to the inner cache does not keep the
+// handle itself reachable: once the handle is garbage collected, its finalizer stops
+// the goroutine, letting the inner cache be collected in turn.
 type MCache[K comparable, V any] struct {
+	*mCache[K, V]
+}
+
+type mCache[K comparable, V any] struct {
 	baseCache
-	m            map[K]valueWithTimeout[V] // where the key-value pairs are stored
-	stopCh       chan struct{}             // Channel to signal timeout goroutine to stop
-	timeInterval time.Duration             // Time interval to sleep the goroutine that checks for expired keys
+	m        map[K]valueWithTimeout[V] // where the key-value pairs are stored
+	expHeap  expHeap[K]                // min-heap of pending expirations, ordered by deadline
+	nextGen  uint64                    // monotonic counter stamped onto each TTL'd entry and its heap entry
+	wakeCh   chan struct{}             // wakes the scheduler when an earlier deadline is inserted
+	stopCh   chan struct{}             // Channel to signal the scheduler goroutine to stop
+	stopOnce sync.Once                 // Ensures stopCh is closed at most once
+
+	// onEvict/onInsertion are stored behind an atomic.Pointer rather than guarded by
+	// c.mu, since the dispatch helpers read them after c.mu has already been released
+	// (so the callbacks can safely call back into the cache without deadlocking).
+	onEvict     atomic.Pointer[evictCallback[K, V]]
+	onInsertion atomic.Pointer[insertionCallback[K, V]]
+
+	jitterDeviation float64
+	rng             *rand.Rand
+
+	sfGroup singleflight.Group
 }
 
 type valueWithTimeout[V any] struct {
-	value    V
-	expireAt *time.Time
+	value      V
+	expireAt   *time.Time
+	generation uint64 // must match the heap entry's generation for that entry to be honored
 }
 
 // New creates a new cache instance with optional configuration provided by the specified options.
-// The database starts a background goroutine to periodically check for expired keys based on the configured time interval.
+// The database starts a background goroutine that sleeps until the next entry is due to expire,
+// rather than polling on a fixed interval.
 func newManual[K comparable, V any](cacheBuilder *CacheBuilder[K, V]) *MCache[K, V] {
-	c := &MCache[K, V]{
-		m:            make(map[K]valueWithTimeout[V]),
-		stopCh:       make(chan struct{}),
-		timeInterval: cacheBuilder.tmIvl,
+	inner := &mCache[K, V]{
+		m:      make(map[K]valueWithTimeout[V]),
+		wakeCh: make(chan struct{}, 1),
+		stopCh: make(chan struct{}),
 		baseCache: baseCache{
 			size: cacheBuilder.size,
 		},
+		jitterDeviation: cacheBuilder.jitterDeviation,
 	}
-	if c.timeInterval > 0 {
-		go c.expireKeys()
+	if inner.jitterDeviation > 0 {
+		src := cacheBuilder.jitterSource
+		if src == nil {
+			src = rand.NewSource(time.Now().UnixNano())
+		}
+		inner.rng = rand.New(src)
 	}
+
+	go inner.runScheduler()
+
+	c := &MCache[K, V]{mCache: inner}
+	runtime.SetFinalizer(c, func(c *MCache[K, V]) {
+		c.stop()
+	})
 	return c
 }
 
+// stop signals the scheduler goroutine to exit. It is safe to call more than once,
+// whether from Purge or from the finalizer set in newManual.
+func (c *mCache[K, V]) stop() {
+	c.stopOnce.Do(func() {
+		close(c.stopCh)
+	})
+}
+
+// jitteredTimeout smears timeout by the configured jitter deviation, so a burst of
+// entries inserted with the same TTL don't all expire at the same instant. It must be
+// called with c.mu held, since the underlying rand.Rand is not safe for concurrent use.
+func (c *mCache[K, V]) jitteredTimeout(timeout time.Duration) time.Duration {
+	if c.jitterDeviation <= 0 {
+		return timeout
+	}
+	factor := 1 + c.jitterDeviation*(2*c.rng.Float64()-1)
+	jittered := time.Duration(float64(timeout) * factor)
+	if jittered < time.Millisecond {
+		return time.Millisecond
+	}
+	return jittered
+}
+
+// wake nudges the scheduler goroutine to recompute its sleep duration, e.g. because an
+// earlier deadline was just inserted. It never blocks: if a wake is already pending the
+// scheduler hasn't consumed yet, this is a no-op.
+func (c *mCache[K, V]) wake() {
+	select {
+	case c.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+// OnEvict registers a callback invoked whenever an entry leaves the cache, whether by
+// explicit deletion, capacity eviction or TTL expiration. The callback is dispatched
+// after the internal lock has been released, so it is safe to call back into the
+// cache from within it.
+func (c *mCache[K, V]) OnEvict(fn func(k K, v V, reason EvictionReason)) {
+	cb := evictCallback[K, V](fn)
+	c.onEvict.Store(&cb)
+}
+
+// OnInsertion registers a callback invoked whenever a new key-value pair is inserted
+// into the cache. The callback is dispatched after the internal lock has been
+// released, so it is safe to call back into the cache from within it.
+func (c *mCache[K, V]) OnInsertion(fn func(k K, v V)) {
+	cb := insertionCallback[K, V](fn)
+	c.onInsertion.Store(&cb)
+}
+
+func (c *mCache[K, V]) dispatchEvictions(events []evictionEvent[K, V]) {
+	cb := c.onEvict.Load()
+	if cb == nil {
+		return
+	}
+	for _, e := range events {
+		(*cb)(e.key, e.value, e.reason)
+	}
+}
+
+func (c *mCache[K, V]) dispatchInsertion(k K, v V) {
+	if cb := c.onInsertion.Load(); cb != nil {
+		(*cb)(k, v)
+	}
+}
+
 // Set adds or updates a key-value pair in the database without setting an expiration time.
 // If the key already exists, its value will be overwritten with the new value.
 // This function is safe for concurrent use.
-func (c *MCache[K, V]) Set(k K, v V) {
+func (c *mCache[K, V]) Set(k K, v V) {
 	if c.size == 0 {
 		return
 	}
 
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
+	var events []evictionEvent[K, V]
 	if len(c.m) == int(c.size) {
-		c.evict(1)
+		events = c.evict(1)
 	}
 
 	c.m[k] = valueWithTimeout[V]{
 		value:    v,
 		expireAt: nil,
 	}
+	c.mu.Unlock()
+
+	c.dispatchEvictions(events)
+	c.dispatchInsertion(k, v)
 }
 
 // NotFoundSet adds a key-value pair to the database if the key does not already exist and returns true. Otherwise, it does nothing and returns false.
-func (c *MCache[K, V]) NotFoundSet(k K, v V) bool {
+func (c *mCache[K, V]) NotFoundSet(k K, v V) bool {
 	if c.size == 0 {
 		return false
 	}
 
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	_, ok := c.m[k]
+	var events []evictionEvent[K, V]
 	if !ok {
 		if len(c.m) == int(c.size) {
-			c.evict(1)
+			events = c.evict(1)
 		}
 
 		c.m[k] = valueWithTimeout[V]{
@@ -74,29 +186,43 @@ func (c *MCache[K, V]) NotFoundSet(k K, v V) bool {
 			expireAt: nil,
 		}
 	}
+	c.mu.Unlock()
+
+	c.dispatchEvictions(events)
+	if !ok {
+		c.dispatchInsertion(k, v)
+	}
 	return !ok
 }
 
 // SetWithTimeout adds or updates a key-value pair in the database with an expiration time.
 // If the timeout duration is zero or negative, the key-value pair will not have an expiration time.
 // This function is safe for concurrent use.
-func (c *MCache[K, V]) SetWithTimeout(k K, v V, timeout time.Duration) {
+func (c *mCache[K, V]) SetWithTimeout(k K, v V, timeout time.Duration) {
 	if c.size == 0 {
 		return
 	}
 	if timeout > 0 {
 		c.mu.Lock()
-		defer c.mu.Unlock()
-
+		var events []evictionEvent[K, V]
 		if len(c.m) == int(c.size) {
-			c.evict(1)
+			events = c.evict(1)
 		}
 
-		now := time.Now().Add(timeout)
+		now := time.Now().Add(c.jitteredTimeout(timeout))
+		c.nextGen++
+		gen := c.nextGen
 		c.m[k] = valueWithTimeout[V]{
-			value:    v,
-			expireAt: &now,
+			value:      v,
+			expireAt:   &now,
+			generation: gen,
 		}
+		heap.Push(&c.expHeap, expEntry[K]{key: k, expireAt: now, generation: gen})
+		c.mu.Unlock()
+
+		c.dispatchEvictions(events)
+		c.dispatchInsertion(k, v)
+		c.wake()
 	} else {
 		c.Set(k, v)
 	}
@@ -105,33 +231,38 @@ func (c *MCache[K, V]) SetWithTimeout(k K, v V, timeout time.Duration) {
 // NotFoundSetWithTimeout adds a key-value pair to the database with an expiration time if the key does not already exist and returns true. Otherwise, it does nothing and returns false.
 // If the timeout is zero or negative, the key-value pair will not have an expiration time.
 // If expiry is disabled, it behaves like NotFoundSet.
-func (c *MCache[K, V]) NotFoundSetWithTimeout(k K, v V, timeout time.Duration) bool {
+func (c *mCache[K, V]) NotFoundSetWithTimeout(k K, v V, timeout time.Duration) bool {
 	if c.size == 0 {
 		return false
 	}
 
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	var ok bool
+	var events []evictionEvent[K, V]
+	var scheduled bool
 	if timeout > 0 {
-		now := time.Now().Add(timeout)
+		now := time.Now().Add(c.jitteredTimeout(timeout))
 		_, ok = c.m[k]
 		if !ok {
 			if len(c.m) == int(c.size) {
-				c.evict(1)
+				events = c.evict(1)
 			}
 
+			c.nextGen++
+			gen := c.nextGen
 			c.m[k] = valueWithTimeout[V]{
-				value:    v,
-				expireAt: &now,
+				value:      v,
+				expireAt:   &now,
+				generation: gen,
 			}
+			heap.Push(&c.expHeap, expEntry[K]{key: k, expireAt: now, generation: gen})
+			scheduled = true
 		}
 	} else {
 		_, ok = c.m[k]
 		if !ok {
 			if len(c.m) == int(c.size) {
-				c.evict(1)
+				events = c.evict(1)
 			}
 
 			c.m[k] = valueWithTimeout[V]{
@@ -140,46 +271,70 @@ func (c *MCache[K, V]) NotFoundSetWithTimeout(k K, v V, timeout time.Duration) b
 			}
 		}
 	}
+	c.mu.Unlock()
+
+	c.dispatchEvictions(events)
+	if !ok {
+		c.dispatchInsertion(k, v)
+	}
+	if scheduled {
+		c.wake()
+	}
 	return !ok
 }
 
-func (c *MCache[K, V]) Get(k K) (v V, b bool) {
+func (c *mCache[K, V]) Get(k K) (v V, b bool) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	val, ok := c.m[k]
 	if !ok {
+		c.mu.Unlock()
 		return
 	}
 	if val.expireAt != nil && val.expireAt.Before(time.Now()) {
 		delete(c.m, k)
+		c.mu.Unlock()
+		c.dispatchEvictions([]evictionEvent[K, V]{{key: k, value: val.value, reason: EvictionExpired}})
 		return
 	}
+	c.mu.Unlock()
 	return val.value, ok
 }
 
-func (c *MCache[K, V]) GetAll() map[K]V {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+func (c *mCache[K, V]) GetAll() map[K]V {
+	c.mu.Lock()
+	now := time.Now()
 	m := make(map[K]V)
+	var events []evictionEvent[K, V]
 	for k, v := range c.m {
-		if v.expireAt == nil || !v.expireAt.Before(time.Now()) {
+		if v.expireAt == nil || !v.expireAt.Before(now) {
 			m[k] = v.value
+		} else {
+			delete(c.m, k)
+			events = append(events, evictionEvent[K, V]{key: k, value: v.value, reason: EvictionExpired})
 		}
 	}
+	c.mu.Unlock()
+
+	c.dispatchEvictions(events)
 	return m
 }
 
-func (c *MCache[K, V]) Delete(k K) {
+func (c *mCache[K, V]) Delete(k K) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	val, ok := c.m[k]
 	delete(c.m, k)
+	c.mu.Unlock()
+
+	if ok {
+		c.dispatchEvictions([]evictionEvent[K, V]{{key: k, value: val.value, reason: EvictionDeleted}})
+	}
 }
 
 // TransferTo transfers all key-value pairs from the source cache to the provided destination cache.
 //
 // The source cache and the destination cache are locked during the entire operation.
 // The function is safe to call concurrently with other operations on any of the source cache or destination cache.
-func (src *MCache[K, V]) TransferTo(dst Cache[K, V]) {
+func (src *mCache[K, V]) TransferTo(dst Cache[K, V]) {
 	all := src.GetAll()
 	src.mu.Lock()
 	src.m = make(map[K]valueWithTimeout[V])
@@ -194,7 +349,7 @@ func (src *MCache[K, V]) TransferTo(dst Cache[K, V]) {
 //
 // The source cache are the destination cache are locked during the entire operation.
 // The function is safe to call concurrently with other operations on any of the source cache or Destination cache.
-func (src *MCache[K, V]) CopyTo(dst Cache[K, V]) {
+func (src *mCache[K, V]) CopyTo(dst Cache[K, V]) {
 	all := src.GetAll()
 
 	for k, v := range all {
@@ -203,7 +358,7 @@ func (src *MCache[K, V]) CopyTo(dst Cache[K, V]) {
 }
 
 // Keys returns a slice containing the keys of the map in random order.
-func (c *MCache[K, V]) Keys() []K {
+func (c *mCache[K, V]) Keys() []K {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -217,20 +372,53 @@ func (c *MCache[K, V]) Keys() []K {
 	return keys
 }
 
-// expireKeys is a background goroutine that periodically checks for expired keys and removes them from the database.
-// It runs until the Close method is callec.
+// runScheduler is a background goroutine that pops due entries off expHeap and removes
+// them from the database, sleeping via a single timer reset to the next deadline
+// instead of polling on a fixed interval. It runs until stop is called.
 // This function is not intended to be called directly by users.
-func (c *MCache[K, V]) expireKeys() {
-	ticker := time.NewTicker(c.timeInterval)
-	defer ticker.Stop()
+func (c *mCache[K, V]) runScheduler() {
+	timer := time.NewTimer(time.Hour)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	defer timer.Stop()
+
 	for {
+		c.mu.Lock()
+		now := time.Now()
+		var events []evictionEvent[K, V]
+		for len(c.expHeap) > 0 && !c.expHeap[0].expireAt.After(now) {
+			entry := heap.Pop(&c.expHeap).(expEntry[K])
+			val, ok := c.m[entry.key]
+			if ok && val.generation == entry.generation {
+				delete(c.m, entry.key)
+				events = append(events, evictionEvent[K, V]{key: entry.key, value: val.value, reason: EvictionExpired})
+			}
+		}
+
+		hasNext := len(c.expHeap) > 0
+		var next time.Duration
+		if hasNext {
+			next = c.expHeap[0].expireAt.Sub(now)
+			if next < 0 {
+				next = 0
+			}
+		}
+		c.mu.Unlock()
+
+		c.dispatchEvictions(events)
+
+		if hasNext {
+			timer.Reset(next)
+		}
+
 		select {
-		case <-ticker.C:
-			for k, v := range c.m {
-				if v.expireAt != nil && v.expireAt.Before(time.Now()) {
-					c.mu.Lock()
-					delete(c.m, k)
-					c.mu.Unlock()
+		case <-timer.C:
+		case <-c.wakeCh:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
 				}
 			}
 		case <-c.stopCh:
@@ -239,37 +427,46 @@ func (c *MCache[K, V]) expireKeys() {
 	}
 }
 
-func (c *MCache[K, V]) Purge() {
-	if c.timeInterval > 0 {
-		c.stopCh <- struct{}{} // Signal the expiration goroutine to stop
-		close(c.stopCh)
-	}
-	c.m = nil
+func (c *mCache[K, V]) Purge() {
+	c.stop()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.m = make(map[K]valueWithTimeout[V])
 }
 
 // Count returns the number of key-value pairs in the database.
-func (c *MCache[K, V]) Count() int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
+func (c *mCache[K, V]) Count() int {
+	c.mu.Lock()
+	now := time.Now()
 	var count int
-	for _, v := range c.m {
-		if v.expireAt == nil || !v.expireAt.Before(time.Now()) {
+	var events []evictionEvent[K, V]
+	for k, v := range c.m {
+		if v.expireAt == nil || !v.expireAt.Before(now) {
 			count++
+		} else {
+			delete(c.m, k)
+			events = append(events, evictionEvent[K, V]{key: k, value: v.value, reason: EvictionExpired})
 		}
 	}
+	c.mu.Unlock()
 
+	c.dispatchEvictions(events)
 	return count
 }
 
-func (c *MCache[K, V]) Len() int {
+func (c *mCache[K, V]) Len() int {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	return len(c.m)
 }
 
-func (c *MCache[K, V]) evict(i int) {
+// evict removes up to i entries from the cache, preferring already-expired entries
+// over live ones. It must be called with c.mu held, and returns the eviction events
+// to dispatch once the caller has released the lock.
+func (c *mCache[K, V]) evict(i int) []evictionEvent[K, V] {
+	events := make([]evictionEvent[K, V], 0, i)
 	var counter int
 	for k, v := range c.m {
 		if counter == i {
@@ -277,6 +474,7 @@ func (c *MCache[K, V]) evict(i int) {
 		}
 		if v.expireAt != nil && !v.expireAt.After(time.Now()) {
 			delete(c.m, k)
+			events = append(events, evictionEvent[K, V]{key: k, value: v.value, reason: EvictionExpired})
 			counter++
 		}
 	}
@@ -284,9 +482,11 @@ func (c *MCache[K, V]) evict(i int) {
 		i = len(c.m)
 	}
 	for ; counter < i; counter++ {
-		for k := range c.m {
+		for k, v := range c.m {
 			delete(c.m, k)
+			events = append(events, evictionEvent[K, V]{key: k, value: v.value, reason: EvictionCapacity})
 			break
 		}
 	}
+	return events
 }
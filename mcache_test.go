@@ -0,0 +1,69 @@
+package incache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestMCacheStopStopsScheduler verifies that stop (called by Purge, or by the
+// finalizer registered in newManual once the MCache handle is collected) actually
+// terminates the background scheduler goroutine rather than leaking it. It builds the
+// inner mCache directly, rather than going through newManual, so it can run its own
+// runScheduler instance instead of racing the one newManual already starts.
+func TestMCacheStopStopsScheduler(t *testing.T) {
+	inner := &mCache[string, int]{
+		m:         make(map[string]valueWithTimeout[int]),
+		wakeCh:    make(chan struct{}, 1),
+		stopCh:    make(chan struct{}),
+		baseCache: baseCache{size: 10},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		inner.runScheduler()
+		close(done)
+	}()
+
+	inner.stop()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("runScheduler did not exit after stop")
+	}
+}
+
+// TestMCacheStopIsIdempotent verifies stop is safe to call more than once, since both
+// Purge and the finalizer may race to call it on the same cache.
+func TestMCacheStopIsIdempotent(t *testing.T) {
+	c := newManual[string, int](&CacheBuilder[string, int]{size: 10})
+	defer c.stop()
+
+	c.stop()
+	c.stop()
+}
+
+// TestMCachePurgeConcurrentWithSet drives Purge concurrently with Set/Get so -race can
+// catch any unsynchronized access to c.m, such as Purge nil-ing or replacing it outside
+// of c.mu while a Set-family call is reading/writing it.
+func TestMCachePurgeConcurrentWithSet(t *testing.T) {
+	c := newManual[string, int](&CacheBuilder[string, int]{size: 100})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			c.Set("k", i)
+			c.Get("k")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			c.Purge()
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,26 @@
+package incache
+
+import "math/rand"
+
+// WithExpiryJitter smears TTLs set via SetWithTimeout and NotFoundSetWithTimeout by a
+// random factor in [1-deviation, 1+deviation), so a burst of entries inserted with the
+// same timeout don't all expire at the same instant and stampede the backing store.
+// For example, a deviation of 0.05 turns a 10s timeout into somewhere between 9.5s and
+// 10.5s. deviation <= 0 disables jitter, which is the default. A deviation above 1 is
+// clamped to 1, so the jittered factor never goes negative; at 1 the factor can still
+// reach zero, and the cache then raises the jittered timeout to 1ms.
+func (b *CacheBuilder[K, V]) WithExpiryJitter(deviation float64) *CacheBuilder[K, V] {
+	if deviation > 1 {
+		deviation = 1
+	}
+	b.jitterDeviation = deviation
+	return b
+}
+
+// WithExpiryJitterSource overrides the random source used to compute jitter. It exists
+// primarily so tests can supply a deterministic source instead of the default
+// time-seeded one.
+func (b *CacheBuilder[K, V]) WithExpiryJitterSource(source rand.Source) *CacheBuilder[K, V] {
+	b.jitterSource = source
+	return b
+}
@@ -0,0 +1,76 @@
+package incache
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestWithExpiryJitterClampsDeviation(t *testing.T) {
+	b := (&CacheBuilder[string, int]{}).WithExpiryJitter(5)
+	if b.jitterDeviation != 1 {
+		t.Fatalf("jitterDeviation = %v, want 1 (clamped)", b.jitterDeviation)
+	}
+
+	b = (&CacheBuilder[string, int]{}).WithExpiryJitter(0.25)
+	if b.jitterDeviation != 0.25 {
+		t.Fatalf("jitterDeviation = %v, want 0.25 (unclamped)", b.jitterDeviation)
+	}
+}
+
+// TestJitteredTimeoutRange asserts that, for a fixed deviation, jitteredTimeout always
+// stays within [1-deviation, 1+deviation] of the requested timeout (floored at 1ms),
+// using a deterministic source so the test itself doesn't flake.
+func TestJitteredTimeoutRange(t *testing.T) {
+	const timeout = 10 * time.Second
+	const deviation = 0.2
+
+	c := newLRU[string, int](&CacheBuilder[string, int]{
+		size:            10,
+		jitterDeviation: deviation,
+		jitterSource:    rand.NewSource(1),
+	})
+
+	min := time.Duration(float64(timeout) * (1 - deviation))
+	max := time.Duration(float64(timeout) * (1 + deviation))
+
+	for i := 0; i < 50; i++ {
+		got := c.jitteredTimeout(timeout)
+		if got < min || got > max {
+			t.Fatalf("jitteredTimeout(%v) = %v, want in [%v, %v]", timeout, got, min, max)
+		}
+	}
+}
+
+// TestJitteredTimeoutDeterministicSource asserts that two caches built with the same
+// fixed source produce the same sequence of jittered durations, so WithExpiryJitterSource
+// is actually usable for reproducible tests.
+func TestJitteredTimeoutDeterministicSource(t *testing.T) {
+	newCache := func() *LRUCache[string, int] {
+		return newLRU[string, int](&CacheBuilder[string, int]{
+			size:            10,
+			jitterDeviation: 0.3,
+			jitterSource:    rand.NewSource(42),
+		})
+	}
+
+	a := newCache()
+	b := newCache()
+
+	for i := 0; i < 10; i++ {
+		got, want := a.jitteredTimeout(time.Second), b.jitteredTimeout(time.Second)
+		if got != want {
+			t.Fatalf("iteration %d: a=%v b=%v, want equal for the same source", i, got, want)
+		}
+	}
+}
+
+// TestJitteredTimeoutDisabled asserts a non-positive deviation disables jitter, as
+// documented on WithExpiryJitter.
+func TestJitteredTimeoutDisabled(t *testing.T) {
+	c := newLRU[string, int](&CacheBuilder[string, int]{size: 10})
+	const timeout = 5 * time.Second
+	if got := c.jitteredTimeout(timeout); got != timeout {
+		t.Fatalf("jitteredTimeout(%v) = %v, want unchanged (jitter disabled)", timeout, got)
+	}
+}
@@ -0,0 +1,28 @@
+package incache
+
+import "time"
+
+// WithAge sets a default max-age applied to entries set via Set or NotFoundSet (i.e.
+// without an explicit timeout). It has no effect on LRUCache instances created with
+// NewLRU, which does not go through the builder.
+func (b *CacheBuilder[K, V]) WithAge(maxAge time.Duration) *CacheBuilder[K, V] {
+	b.defaultMaxAge = maxAge
+	return b
+}
+
+// WithUpdateAgeOnGet enables sliding expiration on LRUCache: each Get refreshes an
+// entry's deadline to now plus its original TTL, instead of the deadline being fixed
+// at insertion time.
+func (b *CacheBuilder[K, V]) WithUpdateAgeOnGet(enabled bool) *CacheBuilder[K, V] {
+	b.updateAgeOnGet = enabled
+	return b
+}
+
+// WithStale enables stale-return mode on LRUCache: GetWithStale returns an expired
+// entry (with its stale result set to true) instead of deleting it, so callers can
+// serve stale-while-revalidate. Plain Get returns the same value but without the
+// stale indicator, since it is implemented in terms of GetWithStale.
+func (b *CacheBuilder[K, V]) WithStale(enabled bool) *CacheBuilder[K, V] {
+	b.staleOnExpiry = enabled
+	return b
+}
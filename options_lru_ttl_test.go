@@ -0,0 +1,97 @@
+package incache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUCacheWithAgeAppliesDefaultMaxAge(t *testing.T) {
+	c := newLRU[string, int](&CacheBuilder[string, int]{size: 10, defaultMaxAge: 10 * time.Millisecond})
+
+	c.Set("a", 1)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a should still be present immediately after Set")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("a should have expired per the default max-age")
+	}
+}
+
+func TestLRUCacheWithAgeIgnoredByExplicitTimeout(t *testing.T) {
+	c := newLRU[string, int](&CacheBuilder[string, int]{size: 10, defaultMaxAge: 5 * time.Millisecond})
+
+	c.SetWithTimeout("a", 1, 0) // explicit "no expiration", must not fall back to defaultMaxAge
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a should not have expired: SetWithTimeout(k, v, 0) means no expiration")
+	}
+}
+
+func TestLRUCacheWithUpdateAgeOnGetSlidesExpiration(t *testing.T) {
+	c := newLRU[string, int](&CacheBuilder[string, int]{size: 10, updateAgeOnGet: true})
+
+	c.SetWithTimeout("a", 1, 30*time.Millisecond)
+
+	// Keep touching the key via Get, each time refreshing its deadline, and confirm it
+	// survives well past its original TTL.
+	deadline := time.Now().Add(90 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		if _, ok := c.Get("a"); !ok {
+			t.Fatal("a expired despite being refreshed on every Get")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Once Get stops being called, the last refreshed deadline should still elapse.
+	time.Sleep(50 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("a should eventually expire once no longer refreshed")
+	}
+}
+
+func TestLRUCacheGetWithStale(t *testing.T) {
+	c := newLRU[string, int](&CacheBuilder[string, int]{size: 10, staleOnExpiry: true})
+
+	c.SetWithTimeout("a", 1, 10*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+
+	v, ok, stale := c.GetWithStale("a")
+	if !ok || !stale {
+		t.Fatalf("GetWithStale(a) = (%v, %v, %v), want (1, true, true)", v, ok, stale)
+	}
+	if v != 1 {
+		t.Fatalf("v = %d, want 1", v)
+	}
+
+	// Get itself should still return the stale value, just without the indicator.
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = (%v, %v), want (1, true)", v, ok)
+	}
+}
+
+func TestLRUCacheGetWithStaleNotStaleWhenFresh(t *testing.T) {
+	c := newLRU[string, int](&CacheBuilder[string, int]{size: 10, staleOnExpiry: true})
+
+	c.SetWithTimeout("a", 1, time.Hour)
+
+	v, ok, stale := c.GetWithStale("a")
+	if !ok || stale {
+		t.Fatalf("GetWithStale(a) = (%v, %v, %v), want (1, true, false)", v, ok, stale)
+	}
+}
+
+func TestLRUCacheGetWithStaleDisabledDeletesExpired(t *testing.T) {
+	c := newLRU[string, int](&CacheBuilder[string, int]{size: 10}) // staleOnExpiry not set
+
+	c.SetWithTimeout("a", 1, 10*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+
+	if _, ok, stale := c.GetWithStale("a"); ok || stale {
+		t.Fatalf("GetWithStale(a) = (_, %v, %v), want (_, false, false) without WithStale", ok, stale)
+	}
+	if c.Len() != 0 {
+		t.Fatal("expired entry should have been deleted without WithStale")
+	}
+}
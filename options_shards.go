@@ -0,0 +1,10 @@
+package incache
+
+// WithShards selects the sharded cache backend, fanning the cache out across n
+// independent LRU shards (each with its own lock) instead of a single LRUCache or
+// MCache behind one global mutex. n is rounded up to the nearest power of two so
+// shard selection can be a mask instead of a modulo.
+func (b *CacheBuilder[K, V]) WithShards(n int) *CacheBuilder[K, V] {
+	b.shardCount = n
+	return b
+}
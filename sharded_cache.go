@@ -0,0 +1,187 @@
+package incache
+
+import (
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// ShardedCache fans a cache out across N independent LRU shards, each with its own
+// lock and eviction list, so that unrelated keys never contend on the same mutex. N is
+// always rounded up to a power of two so shard selection is a mask rather than a mod.
+//
+// newSharded is wired up to CacheBuilder.WithShards via CacheBuilder.Build, defined
+// alongside baseCache and Cache elsewhere in this package.
+type ShardedCache[K comparable, V any] struct {
+	shards []*LRUCache[K, V]
+	mask   uint64
+}
+
+// newSharded creates a new sharded cache instance configured via the provided builder
+// options, with cacheBuilder.shardCount rounded up to the nearest power of two.
+func newSharded[K comparable, V any](cacheBuilder *CacheBuilder[K, V]) *ShardedCache[K, V] {
+	n := nextPowerOfTwo(cacheBuilder.shardCount)
+
+	// Distribute cacheBuilder.size across the n shards as evenly as possible instead of
+	// truncating via plain integer division, which would silently drop up to n-1 slots.
+	base := cacheBuilder.size / uint(n)
+	rem := cacheBuilder.size % uint(n)
+
+	shards := make([]*LRUCache[K, V], n)
+	for i := range shards {
+		shardSize := base
+		if uint(i) < rem {
+			shardSize++
+		}
+		if shardSize == 0 {
+			// A size-0 LRUCache never evicts: len(c.m) == int(c.size) only holds at
+			// len 0, so it would behave as effectively unbounded rather than disabled.
+			// Guarantee every shard can hold at least one entry instead.
+			shardSize = 1
+		}
+
+		shardBuilder := &CacheBuilder[K, V]{
+			size:            shardSize,
+			jitterDeviation: cacheBuilder.jitterDeviation,
+			jitterSource:    cacheBuilder.jitterSource,
+			defaultMaxAge:   cacheBuilder.defaultMaxAge,
+			updateAgeOnGet:  cacheBuilder.updateAgeOnGet,
+			staleOnExpiry:   cacheBuilder.staleOnExpiry,
+		}
+		shards[i] = newLRU(shardBuilder)
+	}
+
+	return &ShardedCache[K, V]{
+		shards: shards,
+		mask:   uint64(n - 1),
+	}
+}
+
+// hasherPool recycles fnv64a hashers across shardFor calls, since allocating one per
+// Get/Set/Delete would otherwise add real overhead to what is supposed to be the
+// uncontended hot path. hashing still formats k via fmt.Sprint, which does allocate;
+// avoiding that generically would require reflecting over K's underlying kind, which
+// isn't worth the complexity for the key types this cache is used with in practice.
+var hasherPool = sync.Pool{
+	New: func() any { return fnv.New64a() },
+}
+
+func nextPowerOfTwo(n int) int {
+	if n < 1 {
+		return 1
+	}
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// shardFor returns the shard responsible for k.
+func (c *ShardedCache[K, V]) shardFor(k K) *LRUCache[K, V] {
+	h := hasherPool.Get().(hash.Hash64)
+	h.Reset()
+	_, _ = h.Write([]byte(fmt.Sprint(k)))
+	sum := h.Sum64()
+	hasherPool.Put(h)
+	return c.shards[sum&c.mask]
+}
+
+func (c *ShardedCache[K, V]) Set(k K, v V) {
+	c.shardFor(k).Set(k, v)
+}
+
+func (c *ShardedCache[K, V]) NotFoundSet(k K, v V) bool {
+	return c.shardFor(k).NotFoundSet(k, v)
+}
+
+func (c *ShardedCache[K, V]) SetWithTimeout(k K, v V, timeout time.Duration) {
+	c.shardFor(k).SetWithTimeout(k, v, timeout)
+}
+
+func (c *ShardedCache[K, V]) NotFoundSetWithTimeout(k K, v V, timeout time.Duration) bool {
+	return c.shardFor(k).NotFoundSetWithTimeout(k, v, timeout)
+}
+
+func (c *ShardedCache[K, V]) Get(k K) (V, bool) {
+	return c.shardFor(k).Get(k)
+}
+
+// GetWithStale behaves like Get, but in addition reports whether the returned value was
+// stale. See LRUCache.GetWithStale; sharding just forwards to the responsible shard.
+func (c *ShardedCache[K, V]) GetWithStale(k K) (v V, ok bool, stale bool) {
+	return c.shardFor(k).GetWithStale(k)
+}
+
+func (c *ShardedCache[K, V]) GetOrLoad(k K, loader func(K) (V, time.Duration, error)) (V, error) {
+	return c.shardFor(k).GetOrLoad(k, loader)
+}
+
+func (c *ShardedCache[K, V]) Delete(k K) {
+	c.shardFor(k).Delete(k)
+}
+
+// OnEvict registers a callback invoked whenever an entry leaves any shard. It is
+// applied to every shard, so it fires regardless of which shard an evicted key
+// happened to land on.
+func (c *ShardedCache[K, V]) OnEvict(fn func(k K, v V, reason EvictionReason)) {
+	for _, shard := range c.shards {
+		shard.OnEvict(fn)
+	}
+}
+
+// OnInsertion registers a callback invoked whenever a new key-value pair is inserted
+// into any shard.
+func (c *ShardedCache[K, V]) OnInsertion(fn func(k K, v V)) {
+	for _, shard := range c.shards {
+		shard.OnInsertion(fn)
+	}
+}
+
+// GetAll returns the union of every shard's entries.
+func (c *ShardedCache[K, V]) GetAll() map[K]V {
+	m := make(map[K]V)
+	for _, shard := range c.shards {
+		for k, v := range shard.GetAll() {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+// Keys returns the keys of every shard, in no particular order.
+func (c *ShardedCache[K, V]) Keys() []K {
+	var keys []K
+	for _, shard := range c.shards {
+		keys = append(keys, shard.Keys()...)
+	}
+	return keys
+}
+
+// Count returns the total number of live key-value pairs across all shards.
+func (c *ShardedCache[K, V]) Count() int {
+	var count int
+	for _, shard := range c.shards {
+		count += shard.Count()
+	}
+	return count
+}
+
+// Len returns the total number of entries across all shards, including ones that have
+// expired but have not yet been purged.
+func (c *ShardedCache[K, V]) Len() int {
+	var length int
+	for _, shard := range c.shards {
+		length += shard.Len()
+	}
+	return length
+}
+
+// Purge clears every shard.
+func (c *ShardedCache[K, V]) Purge() {
+	for _, shard := range c.shards {
+		shard.Purge()
+	}
+}
@@ -0,0 +1,57 @@
+package incache
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestNewShardedDistributesCapacity verifies newSharded distributes the requested
+// capacity across shards instead of truncating it away, and never hands out a size-0
+// shard (which would behave as unbounded rather than disabled).
+func TestNewShardedDistributesCapacity(t *testing.T) {
+	sc := newSharded[string, int](&CacheBuilder[string, int]{size: 10, shardCount: 4})
+
+	var total uint
+	for _, shard := range sc.shards {
+		if shard.size == 0 {
+			t.Fatal("shard has size 0")
+		}
+		total += shard.size
+	}
+	if total < 10 {
+		t.Fatalf("total shard capacity %d is less than the requested 10", total)
+	}
+}
+
+// TestNewShardedZeroSize verifies requesting shards with no explicit size still leaves
+// every shard able to hold at least one entry, rather than silently unbounded.
+func TestNewShardedZeroSize(t *testing.T) {
+	sc := newSharded[string, int](&CacheBuilder[string, int]{shardCount: 4})
+
+	for _, shard := range sc.shards {
+		if shard.size == 0 {
+			t.Fatal("shard has size 0")
+		}
+	}
+}
+
+// TestShardedCacheConcurrentAccess drives Set/Get/Delete across shards concurrently so
+// -race can catch any unsynchronized access introduced by the sharding layer itself.
+func TestShardedCacheConcurrentAccess(t *testing.T) {
+	sc := newSharded[string, int](&CacheBuilder[string, int]{size: 100, shardCount: 8})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				k := string(rune('a' + (j+i)%16))
+				sc.Set(k, j)
+				sc.Get(k)
+				sc.Delete(k)
+			}
+		}(i)
+	}
+	wg.Wait()
+}